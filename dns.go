@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dohResolver is one DoH-capable resolver in the failover chain.
+type dohResolver struct {
+	name string
+	url  string
+}
+
+// defaultDoHResolvers are tried in order on every lookup; a resolver that
+// just failed is skipped for resolverBackoff before being retried.
+var defaultDoHResolvers = []dohResolver{
+	{"cloudflare", "https://1.1.1.1/dns-query"},
+	{"google", "https://8.8.8.8/resolve"},
+	{"quad9", "https://9.9.9.9:5053/dns-query"},
+}
+
+// resolverBackoff is how long a resolver that just failed is skipped
+// before it's tried again.
+const resolverBackoff = 5 * time.Minute
+
+const (
+	dnsRecordA    = "A"
+	dnsRecordAAAA = "AAAA"
+)
+
+type dohAnswer struct {
+	Data string `json:"data"`
+	Type int    `json:"type"`
+	TTL  int    `json:"TTL"`
+}
+
+type dohResponse struct {
+	Answer []dohAnswer `json:"Answer"`
+}
+
+// dnsCacheEntry is one resolved (host, record type) pair, persisted to
+// disk so a restart doesn't start cold.
+type dnsCacheEntry struct {
+	IP      string    `json:"ip"`
+	Expires time.Time `json:"expires"`
+}
+
+// DoHResolver resolves hostnames over DNS-over-HTTPS, honoring each
+// answer's TTL and failing over across a chain of resolvers with
+// per-resolver backoff. Unlike a single hardcoded, TLS-unverified lookup
+// against one provider, it applies to any host a caller asks about, so
+// fetch_content benefits the same way the search endpoints do when
+// reaching sites that censor plain DNS.
+type DoHResolver struct {
+	client    *http.Client
+	resolvers []dohResolver
+	cachePath string
+
+	mu       sync.RWMutex
+	cache    map[string]dnsCacheEntry // key: "host/A" or "host/AAAA"
+	backoffs map[string]time.Time     // resolver name -> usable-again time
+}
+
+func NewDoHResolver() *DoHResolver {
+	r := &DoHResolver{
+		client:    &http.Client{Timeout: 5 * time.Second},
+		resolvers: defaultDoHResolvers,
+		cachePath: filepath.Join(os.TempDir(), "goduckduckgo-dns-cache.json"),
+		cache:     make(map[string]dnsCacheEntry),
+		backoffs:  make(map[string]time.Time),
+	}
+	r.loadDiskCache()
+	return r
+}
+
+// Resolve returns an IP address for host, preferring AAAA when network
+// (as passed to a dialer's DialContext) asks for an IPv6-only connection
+// and A otherwise.
+func (r *DoHResolver) Resolve(ctx context.Context, network, host string) (string, error) {
+	recordType := dnsRecordA
+	if strings.Contains(network, "6") {
+		recordType = dnsRecordAAAA
+	}
+
+	key := host + "/" + recordType
+	r.mu.RLock()
+	entry, ok := r.cache[key]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(entry.Expires) {
+		return entry.IP, nil
+	}
+
+	var lastErr error
+	for _, resolver := range r.resolvers {
+		r.mu.RLock()
+		until, backedOff := r.backoffs[resolver.name]
+		r.mu.RUnlock()
+		if backedOff && time.Now().Before(until) {
+			continue
+		}
+
+		ip, ttl, err := r.query(ctx, resolver, host, recordType)
+		if err != nil {
+			lastErr = err
+			r.mu.Lock()
+			r.backoffs[resolver.name] = time.Now().Add(resolverBackoff)
+			r.mu.Unlock()
+			continue
+		}
+
+		r.mu.Lock()
+		r.cache[key] = dnsCacheEntry{IP: ip, Expires: time.Now().Add(ttl)}
+		r.mu.Unlock()
+		return ip, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no resolvers available for %s", host)
+	}
+	return "", lastErr
+}
+
+func (r *DoHResolver) query(ctx context.Context, resolver dohResolver, host, recordType string) (string, time.Duration, error) {
+	q := url.Values{}
+	q.Set("name", host)
+	q.Set("type", recordType)
+	req, err := http.NewRequestWithContext(ctx, "GET", resolver.url+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Accept", "application/dns-json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("%s: status %d", resolver.name, resp.StatusCode)
+	}
+	var doh dohResponse
+	if err := json.NewDecoder(resp.Body).Decode(&doh); err != nil {
+		return "", 0, err
+	}
+	wantTypeNum := 1
+	if recordType == dnsRecordAAAA {
+		wantTypeNum = 28
+	}
+	for _, answer := range doh.Answer {
+		if answer.Type == wantTypeNum {
+			ttl := time.Duration(answer.TTL) * time.Second
+			if ttl <= 0 {
+				ttl = time.Minute
+			}
+			return answer.Data, ttl, nil
+		}
+	}
+	return "", 0, fmt.Errorf("%s: no %s record for %s", resolver.name, recordType, host)
+}
+
+func (r *DoHResolver) loadDiskCache() {
+	data, err := os.ReadFile(r.cachePath)
+	if err != nil {
+		return
+	}
+	var cache map[string]dnsCacheEntry
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, entry := range cache {
+		if now.Before(entry.Expires) {
+			r.cache[key] = entry
+		}
+	}
+}
+
+// Save persists the current, non-expired cache entries to disk so the
+// next startup can skip resolving hosts it already knows about.
+func (r *DoHResolver) Save() {
+	now := time.Now()
+	r.mu.RLock()
+	live := make(map[string]dnsCacheEntry, len(r.cache))
+	for key, entry := range r.cache {
+		if now.Before(entry.Expires) {
+			live[key] = entry
+		}
+	}
+	r.mu.RUnlock()
+	data, err := json.Marshal(live)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(r.cachePath, data, 0o644)
+}
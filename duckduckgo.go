@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
+)
+
+// DuckDuckGoSearcher scrapes DuckDuckGo's HTML-only search endpoint, the
+// same one served to browsers with JavaScript disabled.
+type DuckDuckGoSearcher struct {
+	limiter *rate.Limiter
+	client  *http.Client
+	vqd     *vqdTokenCache
+}
+
+// NewDuckDuckGoSearcher builds a searcher that shares vqd with any other
+// DuckDuckGo-backed searcher (e.g. DuckDuckGoMediaSearcher) passed the same
+// cache, so a query hit on one doesn't force a redundant token fetch on
+// the other.
+func NewDuckDuckGoSearcher(vqd *vqdTokenCache) *DuckDuckGoSearcher {
+	return &DuckDuckGoSearcher{
+		limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+		client:  newAntiCensorshipClient(),
+		vqd:     vqd,
+	}
+}
+
+func (s *DuckDuckGoSearcher) Name() string {
+	return "duckduckgo"
+}
+
+func (s *DuckDuckGoSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+	kp := "-1"
+	switch strings.ToLower(opts.SafeSearch) {
+	case "strict":
+		kp = "1"
+	case "off":
+		kp = "-2"
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	form := url.Values{}
+	form.Set("q", query)
+	form.Set("b", "")
+	form.Set("kl", opts.Region)
+	form.Set("kp", kp)
+	if page > 1 {
+		form.Set("s", fmt.Sprintf("%d", (page-1)*10))
+	}
+	if vqd, err := s.vqd.get(ctx, query); err == nil {
+		form.Set("vqd", vqd)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://html.duckduckgo.com/html", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", uaPool.Pick())
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	maxResults := opts.MaxResults
+	var results []SearchResult
+	doc.Find(".result").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		titleElem := sel.Find(".result__title")
+		if titleElem.Length() == 0 {
+			return true
+		}
+		linkElem := titleElem.Find("a")
+		if linkElem.Length() == 0 {
+			return true
+		}
+		title := strings.TrimSpace(linkElem.Text())
+		link, exists := linkElem.Attr("href")
+		if !exists {
+			return true
+		}
+		if strings.Contains(link, "y.js") {
+			return true
+		}
+		if strings.HasPrefix(link, "//duckduckgo.com/l/?uddg=") {
+			parts := strings.Split(link, "uddg=")
+			if len(parts) > 1 {
+				decoded, err := url.QueryUnescape(strings.Split(parts[1], "&")[0])
+				if err == nil {
+					link = decoded
+				}
+			}
+		}
+		snippetElem := sel.Find(".result__snippet")
+		snippet := ""
+		if snippetElem.Length() > 0 {
+			snippet = strings.TrimSpace(snippetElem.Text())
+		}
+		results = append(results, SearchResult{Title: title, Link: link, Snippet: snippet, Position: len(results) + 1})
+		return len(results) < maxResults
+	})
+	return results, nil
+}
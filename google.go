@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
+)
+
+// GoogleSearcher scrapes Google's classic HTML search results page. It has
+// no official API, so the usual caveats apply: Google may serve a CAPTCHA
+// or alter markup without notice, and this backend should be treated as
+// best-effort relative to DuckDuckGoSearcher.
+type GoogleSearcher struct {
+	limiter *rate.Limiter
+	client  *http.Client
+}
+
+func NewGoogleSearcher() *GoogleSearcher {
+	return &GoogleSearcher{
+		limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+		client:  newAntiCensorshipClient(),
+	}
+}
+
+func (s *GoogleSearcher) Name() string {
+	return "google"
+}
+
+func (s *GoogleSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+	safe := "active"
+	if strings.ToLower(opts.SafeSearch) == "off" {
+		safe = "off"
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("num", strconv.Itoa(maxResults))
+	q.Set("start", strconv.Itoa((page-1)*10))
+	q.Set("safe", safe)
+	if region := normalizeRegion(opts.Region); region != "" {
+		q.Set("hl", region)
+	}
+	reqURL := "https://www.google.com/search?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", uaPool.Pick())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	doc.Find("div.g").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		linkElem := sel.Find("a").First()
+		link, exists := linkElem.Attr("href")
+		if !exists || !strings.HasPrefix(link, "http") {
+			return true
+		}
+		title := strings.TrimSpace(sel.Find("h3").First().Text())
+		if title == "" {
+			return true
+		}
+		snippet := strings.TrimSpace(sel.Find("div.VwiC3b").First().Text())
+		results = append(results, SearchResult{Title: title, Link: link, Snippet: snippet, Position: len(results) + 1})
+		return len(results) < maxResults
+	})
+	return results, nil
+}
@@ -3,13 +3,10 @@ package main
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -25,47 +22,10 @@ type SearchResult struct {
 	Position int
 }
 
-type DoHResponse struct {
-	Answer []struct {
-		Data string `json:"data"`
-		Type int    `json:"type"`
-	} `json:"Answer"`
-}
-
-var (
-	dnsCache = make(map[string]string)
-	dnsMutex sync.RWMutex
-)
-
-func resolveOverDoH(ctx context.Context, domain string) (string, error) {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr, Timeout: 5 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://1.1.1.1/dns-query?name=%s&type=A", domain), nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Accept", "application/dns-json")
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("DoH status: %d", resp.StatusCode)
-	}
-	var doh DoHResponse
-	if err := json.NewDecoder(resp.Body).Decode(&doh); err != nil {
-		return "", err
-	}
-	for _, answer := range doh.Answer {
-		if answer.Type == 1 {
-			return answer.Data, nil
-		}
-	}
-	return "", fmt.Errorf("no A record: %s", domain)
-}
+// dohResolverInstance is the process-wide resolver shared by every
+// anti-censorship client, the same way uaPool is shared by every request
+// that needs a User-Agent.
+var dohResolverInstance = NewDoHResolver()
 
 func newAntiCensorshipClient() *http.Client {
 	return &http.Client{
@@ -77,23 +37,9 @@ func newAntiCensorshipClient() *http.Client {
 				if err != nil {
 					return nil, err
 				}
-				dnsMutex.RLock()
-				ip, found := dnsCache[host]
-				dnsMutex.RUnlock()
-				if !found {
-					if strings.Contains(host, "duckduckgo.com") {
-						resolvedIP, err := resolveOverDoH(ctx, host)
-						if err == nil {
-							ip = resolvedIP
-							dnsMutex.Lock()
-							dnsCache[host] = ip
-							dnsMutex.Unlock()
-						} else {
-							ip = host
-						}
-					} else {
-						ip = host
-					}
+				ip, err := dohResolverInstance.Resolve(ctx, network, host)
+				if err != nil {
+					ip = host
 				}
 				dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
 				return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
@@ -108,55 +54,25 @@ func newAntiCensorshipClient() *http.Client {
 	}
 }
 
-type DuckDuckGoSearcher struct {
+type WebContentFetcher struct {
 	limiter *rate.Limiter
 	client  *http.Client
 }
 
-func NewDuckDuckGoSearcher() *DuckDuckGoSearcher {
-	return &DuckDuckGoSearcher{
-		limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+func NewWebContentFetcher() *WebContentFetcher {
+	return &WebContentFetcher{
+		limiter: rate.NewLimiter(rate.Every(time.Minute/20), 1),
 		client:  newAntiCensorshipClient(),
 	}
 }
 
-func (s *DuckDuckGoSearcher) FormatResultsForLLM(query string, results []SearchResult) string {
-	if len(results) == 0 {
-		return fmt.Sprintf("# GoDuckDuckGo Search Results\n\nNo results found for query: \"%s\"", query)
-	}
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# GoDuckDuckGo Search Results\n\nFound %d results for: \"%s\"\n\n---\n\n", len(results), query))
-	for _, result := range results {
-		sb.WriteString(fmt.Sprintf("### %s\n", result.Title))
-		sb.WriteString(fmt.Sprintf("%s\n\n", result.Snippet))
-		sb.WriteString(fmt.Sprintf("🔗 [Read More](%s)\n\n", result.Link))
-	}
-	return sb.String()
-}
-
-func (s *DuckDuckGoSearcher) Search(ctx context.Context, query string, maxResults int, safeSearch string) ([]SearchResult, error) {
-	if err := s.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit: %w", err)
-	}
-	kp := "-1"
-	switch strings.ToLower(safeSearch) {
-	case "strict":
-		kp = "1"
-	case "off":
-		kp = "-2"
-	}
-	form := url.Values{}
-	form.Set("q", query)
-	form.Set("b", "")
-	form.Set("kl", "")
-	form.Set("kp", kp)
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://html.duckduckgo.com/html", strings.NewReader(form.Encode()))
+func (f *WebContentFetcher) fetchPlain(ctx context.Context, urlStr string) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := s.client.Do(req)
+	req.Header.Set("User-Agent", uaPool.Pick())
+	resp, err := f.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -164,81 +80,45 @@ func (s *DuckDuckGoSearcher) Search(ctx context.Context, query string, maxResult
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("status: %d", resp.StatusCode)
 	}
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var results []SearchResult
-	doc.Find(".result").EachWithBreak(func(i int, sel *goquery.Selection) bool {
-		titleElem := sel.Find(".result__title")
-		if titleElem.Length() == 0 {
-			return true
-		}
-		linkElem := titleElem.Find("a")
-		if linkElem.Length() == 0 {
-			return true
-		}
-		title := strings.TrimSpace(linkElem.Text())
-		link, exists := linkElem.Attr("href")
-		if !exists {
-			return true
-		}
-		if strings.Contains(link, "y.js") {
-			return true
-		}
-		if strings.HasPrefix(link, "//duckduckgo.com/l/?uddg=") {
-			parts := strings.Split(link, "uddg=")
-			if len(parts) > 1 {
-				decoded, err := url.QueryUnescape(strings.Split(parts[1], "&")[0])
-				if err == nil {
-					link = decoded
-				}
-			}
-		}
-		snippetElem := sel.Find(".result__snippet")
-		snippet := ""
-		if snippetElem.Length() > 0 {
-			snippet = strings.TrimSpace(snippetElem.Text())
-		}
-		results = append(results, SearchResult{Title: title, Link: link, Snippet: snippet, Position: len(results) + 1})
-		return len(results) < maxResults
-	})
-	return results, nil
-}
-
-type WebContentFetcher struct {
-	limiter *rate.Limiter
-	client  *http.Client
-}
-
-func NewWebContentFetcher() *WebContentFetcher {
-	return &WebContentFetcher{
-		limiter: rate.NewLimiter(rate.Every(time.Minute/20), 1),
-		client:  newAntiCensorshipClient(),
-	}
+	return goquery.NewDocumentFromReader(resp.Body)
 }
 
-func (f *WebContentFetcher) FetchAndParse(ctx context.Context, urlStr string) (string, error) {
+// FetchAndParse fetches urlStr and extracts its visible text. render
+// controls whether a headless-browser rendering pass is used:
+//
+//	"never"  - always use the plain HTTP fetch.
+//	"always" - always render with chromedp, skipping the plain fetch.
+//	"auto"   - try the plain fetch first, and only render if it fails or
+//	           the result looks like an unrendered SPA shell (see
+//	           looksUnrendered).
+func (f *WebContentFetcher) FetchAndParse(ctx context.Context, urlStr string, render string) (string, error) {
 	if err := f.limiter.Wait(ctx); err != nil {
 		return "", fmt.Errorf("rate limit: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return "", err
+
+	var doc *goquery.Document
+	if render != "always" {
+		plain, err := f.fetchPlain(ctx, urlStr)
+		if err != nil && render == "never" {
+			return "", err
+		}
+		doc = plain
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("status: %d", resp.StatusCode)
+
+	if render == "always" || (render == "auto" && (doc == nil || looksUnrendered(doc))) {
+		html, err := renderWithChromedp(ctx, urlStr)
+		if err == nil {
+			if rendered, perr := goquery.NewDocumentFromReader(strings.NewReader(html)); perr == nil {
+				doc = rendered
+			}
+		} else if doc == nil {
+			return "", fmt.Errorf("chromedp render failed: %w", err)
+		}
 	}
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return "", err
+	if doc == nil {
+		return "", fmt.Errorf("failed to fetch content from %s", urlStr)
 	}
+
 	doc.Find("script, style, nav, header, footer").Remove()
 	text := doc.Text()
 	lines := strings.Split(text, "\\n")
@@ -258,36 +138,135 @@ func (f *WebContentFetcher) FetchAndParse(ctx context.Context, urlStr string) (s
 }
 
 func main() {
-	searcher := NewDuckDuckGoSearcher()
+	vqdCache := newVQDTokenCache(newAntiCensorshipClient())
+	duckduckgo := NewDuckDuckGoSearcher(vqdCache)
+	media := NewDuckDuckGoMediaSearcher(vqdCache)
 	fetcher := NewWebContentFetcher()
+	aggregator := NewMetasearchAggregator(
+		duckduckgo,
+		NewGoogleSearcher(),
+		NewQuantSearcher(),
+		NewSearXNGSearcher(),
+	)
 	s := server.NewMCPServer("GoDuckDuckGo", "1.0.2", server.WithLogging())
 	s.AddTool(mcp.NewTool("search",
 		mcp.WithDescription("Search DuckDuckGo and return formatted results. Ideal for general queries, news, articles, and online content."),
 		mcp.WithString("query", mcp.Description("The search query string")),
 		mcp.WithNumber("max_results", mcp.Description("Maximum number of results to return (default: 10)")),
 		mcp.WithString("safe_search", mcp.Description("SafeSearch level: 'strict', 'moderate', or 'off' (default: 'moderate')"), mcp.Enum("strict", "moderate", "off")),
+		mcp.WithNumber("page", mcp.Description("Result page to fetch, 10 results per page (default: 1)")),
+		mcp.WithString("region", mcp.Description("DuckDuckGo region code to localize results, e.g. 'us-en', 'de-de' (default: 'wt-wt' for no region)")),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := mcp.ParseString(request, "query", "")
+		opts := SearchOptions{
+			MaxResults: int(mcp.ParseInt(request, "max_results", 10)),
+			SafeSearch: mcp.ParseString(request, "safe_search", "moderate"),
+			Page:       int(mcp.ParseInt(request, "page", 1)),
+			Region:     mcp.ParseString(request, "region", "wt-wt"),
+		}
+		results, err := duckduckgo.Search(ctx, query, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("An error occurred while searching: %v", err)), nil
+		}
+		return mcp.NewToolResultText(FormatResultsForLLM("GoDuckDuckGo Search Results", query, results)), nil
+	})
+	s.AddTool(mcp.NewTool("metasearch",
+		mcp.WithDescription("Search DuckDuckGo, Google, Quant, and a pool of SearXNG instances concurrently, then merge and re-rank the combined results. Best for queries where a single engine's blind spots would be costly."),
+		mcp.WithString("query", mcp.Description("The search query string")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of merged results to return (default: 10)")),
+		mcp.WithString("safe_search", mcp.Description("SafeSearch level: 'strict', 'moderate', or 'off' (default: 'moderate')"), mcp.Enum("strict", "moderate", "off")),
+		mcp.WithNumber("page", mcp.Description("Result page to fetch from each backend, 10 results per page (default: 1)")),
+		mcp.WithString("region", mcp.Description("Region/language code to localize results, e.g. 'us-en', 'de-de' (default: 'wt-wt' for no region)")),
 	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		query := mcp.ParseString(request, "query", "")
 		maxResults := int(mcp.ParseInt(request, "max_results", 10))
-		safeSearch := mcp.ParseString(request, "safe_search", "moderate")
-		results, err := searcher.Search(ctx, query, maxResults, safeSearch)
+		opts := SearchOptions{
+			MaxResults: maxResults,
+			SafeSearch: mcp.ParseString(request, "safe_search", "moderate"),
+			Page:       int(mcp.ParseInt(request, "page", 1)),
+			Region:     mcp.ParseString(request, "region", "wt-wt"),
+		}
+		results, err := aggregator.Search(ctx, query, opts, maxResults)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("An error occurred while searching: %v", err)), nil
 		}
-		return mcp.NewToolResultText(searcher.FormatResultsForLLM(query, results)), nil
+		return mcp.NewToolResultText(FormatResultsForLLM("Metasearch Results", query, results)), nil
+	})
+	s.AddTool(mcp.NewTool("search_images",
+		mcp.WithDescription("Search DuckDuckGo for images and return formatted results"),
+		mcp.WithString("query", mcp.Description("The search query string")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of results to return (default: 10)")),
+		mcp.WithString("safe_search", mcp.Description("SafeSearch level: 'strict', 'moderate', or 'off' (default: 'moderate')"), mcp.Enum("strict", "moderate", "off")),
+		mcp.WithNumber("page", mcp.Description("Result page to fetch, 10 results per page (default: 1)")),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := mcp.ParseString(request, "query", "")
+		opts := SearchOptions{
+			MaxResults: int(mcp.ParseInt(request, "max_results", 10)),
+			SafeSearch: mcp.ParseString(request, "safe_search", "moderate"),
+			Page:       int(mcp.ParseInt(request, "page", 1)),
+		}
+		results, err := media.SearchImages(ctx, query, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("An error occurred while searching images: %v", err)), nil
+		}
+		return mcp.NewToolResultText(FormatImageResultsForLLM(query, results)), nil
+	})
+	s.AddTool(mcp.NewTool("search_videos",
+		mcp.WithDescription("Search DuckDuckGo for videos and return formatted results"),
+		mcp.WithString("query", mcp.Description("The search query string")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of results to return (default: 10)")),
+		mcp.WithString("safe_search", mcp.Description("SafeSearch level: 'strict', 'moderate', or 'off' (default: 'moderate')"), mcp.Enum("strict", "moderate", "off")),
+		mcp.WithNumber("page", mcp.Description("Result page to fetch, 10 results per page (default: 1)")),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := mcp.ParseString(request, "query", "")
+		opts := SearchOptions{
+			MaxResults: int(mcp.ParseInt(request, "max_results", 10)),
+			SafeSearch: mcp.ParseString(request, "safe_search", "moderate"),
+			Page:       int(mcp.ParseInt(request, "page", 1)),
+		}
+		results, err := media.SearchVideos(ctx, query, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("An error occurred while searching videos: %v", err)), nil
+		}
+		return mcp.NewToolResultText(FormatVideoResultsForLLM(query, results)), nil
+	})
+	s.AddTool(mcp.NewTool("search_news",
+		mcp.WithDescription("Search DuckDuckGo for news articles and return formatted results"),
+		mcp.WithString("query", mcp.Description("The search query string")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of results to return (default: 10)")),
+		mcp.WithString("safe_search", mcp.Description("SafeSearch level: 'strict', 'moderate', or 'off' (default: 'moderate')"), mcp.Enum("strict", "moderate", "off")),
+		mcp.WithNumber("page", mcp.Description("Result page to fetch, 10 results per page (default: 1)")),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := mcp.ParseString(request, "query", "")
+		opts := SearchOptions{
+			MaxResults: int(mcp.ParseInt(request, "max_results", 10)),
+			SafeSearch: mcp.ParseString(request, "safe_search", "moderate"),
+			Page:       int(mcp.ParseInt(request, "page", 1)),
+		}
+		results, err := media.SearchNews(ctx, query, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("An error occurred while searching news: %v", err)), nil
+		}
+		return mcp.NewToolResultText(FormatNewsResultsForLLM(query, results)), nil
 	})
 	s.AddTool(mcp.NewTool("fetch_content",
 		mcp.WithDescription("Fetch and parse content from a webpage URL"),
 		mcp.WithString("url", mcp.Description("The webpage URL to fetch content from")),
+		mcp.WithString("render", mcp.Description("JavaScript rendering mode: 'auto' falls back to a headless-browser render if the plain fetch looks like an unrendered SPA shell, 'always' always renders, 'never' always uses the plain fetch (default: 'auto')"), mcp.Enum("auto", "always", "never")),
 	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		urlStr := mcp.ParseString(request, "url", "")
-		content, err := fetcher.FetchAndParse(ctx, urlStr)
+		render := mcp.ParseString(request, "render", "auto")
+		content, err := fetcher.FetchAndParse(ctx, urlStr, render)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("An error occurred while fetching content: %v", err)), nil
 		}
 		return mcp.NewToolResultText(content), nil
 	})
+	// server.ServeStdio installs its own SIGINT/SIGTERM handler and returns
+	// once it's triggered, so the Save() below already runs on that path,
+	// not just on stdin EOF.
 	if err := server.ServeStdio(s); err != nil {
 		fmt.Printf("Server error: %v\\n", err)
 	}
+	dohResolverInstance.Save()
 }
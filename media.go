@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type ImageResult struct {
+	Title     string
+	Image     string
+	Thumbnail string
+	Source    string
+	Width     int
+	Height    int
+}
+
+type VideoResult struct {
+	Title     string
+	Content   string
+	Duration  string
+	Published string
+	Embed     string
+}
+
+type NewsResult struct {
+	Title   string
+	Excerpt string
+	URL     string
+	Source  string
+	Date    string
+}
+
+// DuckDuckGoMediaSearcher queries DuckDuckGo's image, video, and news JSON
+// endpoints (i.js, v.js, news.js). Like the HTML endpoint, these require a
+// vqd anti-bot token obtained from the regular results page first.
+type DuckDuckGoMediaSearcher struct {
+	limiter *rate.Limiter
+	client  *http.Client
+	vqd     *vqdTokenCache
+}
+
+// NewDuckDuckGoMediaSearcher builds a media searcher that shares vqd with
+// DuckDuckGoSearcher, so a "search" call and a "search_images"/
+// "search_videos"/"search_news" call for the same query reuse one token
+// instead of each fetching and caching their own.
+func NewDuckDuckGoMediaSearcher(vqd *vqdTokenCache) *DuckDuckGoMediaSearcher {
+	return &DuckDuckGoMediaSearcher{
+		limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+		client:  newAntiCensorshipClient(),
+		vqd:     vqd,
+	}
+}
+
+func safeSearchParam(safeSearch string) string {
+	switch strings.ToLower(safeSearch) {
+	case "strict":
+		return "1"
+	case "off":
+		return "-2"
+	default:
+		return "-1"
+	}
+}
+
+// fetchJSON performs the shared request/response plumbing for the i.js,
+// v.js, and news.js endpoints: rate limit, resolve the vqd token, build the
+// query string, and decode the JSON body into dest.
+func (s *DuckDuckGoMediaSearcher) fetchJSON(ctx context.Context, endpoint, query string, opts SearchOptions, dest interface{}) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit: %w", err)
+	}
+	vqd, err := s.vqd.get(ctx, query)
+	if err != nil {
+		return err
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("vqd", vqd)
+	q.Set("o", "json")
+	q.Set("p", safeSearchParam(opts.SafeSearch))
+	if page > 1 {
+		q.Set("s", fmt.Sprintf("%d", (page-1)*10))
+	}
+	if opts.Region != "" {
+		q.Set("kl", opts.Region)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://duckduckgo.com/"+endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", uaPool.Pick())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+func (s *DuckDuckGoMediaSearcher) SearchImages(ctx context.Context, query string, opts SearchOptions) ([]ImageResult, error) {
+	var raw struct {
+		Results []struct {
+			Title     string `json:"title"`
+			Image     string `json:"image"`
+			Thumbnail string `json:"thumbnail"`
+			Source    string `json:"source"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"results"`
+	}
+	if err := s.fetchJSON(ctx, "i.js", query, opts, &raw); err != nil {
+		return nil, err
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	var results []ImageResult
+	for _, r := range raw.Results {
+		if len(results) >= maxResults {
+			break
+		}
+		results = append(results, ImageResult{
+			Title:     r.Title,
+			Image:     r.Image,
+			Thumbnail: r.Thumbnail,
+			Source:    r.Source,
+			Width:     r.Width,
+			Height:    r.Height,
+		})
+	}
+	return results, nil
+}
+
+func (s *DuckDuckGoMediaSearcher) SearchVideos(ctx context.Context, query string, opts SearchOptions) ([]VideoResult, error) {
+	var raw struct {
+		Results []struct {
+			Title     string `json:"title"`
+			Content   string `json:"content"`
+			Duration  string `json:"duration"`
+			Published string `json:"published"`
+			Embed     string `json:"embed_url"`
+		} `json:"results"`
+	}
+	if err := s.fetchJSON(ctx, "v.js", query, opts, &raw); err != nil {
+		return nil, err
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	var results []VideoResult
+	for _, r := range raw.Results {
+		if len(results) >= maxResults {
+			break
+		}
+		results = append(results, VideoResult{
+			Title:     r.Title,
+			Content:   r.Content,
+			Duration:  r.Duration,
+			Published: r.Published,
+			Embed:     r.Embed,
+		})
+	}
+	return results, nil
+}
+
+func (s *DuckDuckGoMediaSearcher) SearchNews(ctx context.Context, query string, opts SearchOptions) ([]NewsResult, error) {
+	var raw struct {
+		Results []struct {
+			Title   string `json:"title"`
+			Excerpt string `json:"excerpt"`
+			URL     string `json:"url"`
+			Source  string `json:"source"`
+			Date    string `json:"date"`
+		} `json:"results"`
+	}
+	if err := s.fetchJSON(ctx, "news.js", query, opts, &raw); err != nil {
+		return nil, err
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	var results []NewsResult
+	for _, r := range raw.Results {
+		if len(results) >= maxResults {
+			break
+		}
+		results = append(results, NewsResult{
+			Title:   r.Title,
+			Excerpt: r.Excerpt,
+			URL:     r.URL,
+			Source:  r.Source,
+			Date:    r.Date,
+		})
+	}
+	return results, nil
+}
+
+func FormatImageResultsForLLM(query string, results []ImageResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("# GoDuckDuckGo Image Results\n\nNo image results found for query: \"%s\"", query)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# GoDuckDuckGo Image Results\n\nFound %d images for: \"%s\"\n\n---\n\n", len(results), query))
+	for _, result := range results {
+		sb.WriteString(fmt.Sprintf("### %s\n", result.Title))
+		sb.WriteString(fmt.Sprintf("Source: %s (%dx%d)\n", result.Source, result.Width, result.Height))
+		sb.WriteString(fmt.Sprintf("🖼️ [Image](%s)\n\n", result.Image))
+	}
+	return sb.String()
+}
+
+func FormatVideoResultsForLLM(query string, results []VideoResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("# GoDuckDuckGo Video Results\n\nNo video results found for query: \"%s\"", query)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# GoDuckDuckGo Video Results\n\nFound %d videos for: \"%s\"\n\n---\n\n", len(results), query))
+	for _, result := range results {
+		sb.WriteString(fmt.Sprintf("### %s (%s, published %s)\n", result.Title, result.Duration, result.Published))
+		sb.WriteString(fmt.Sprintf("%s\n\n", result.Content))
+		sb.WriteString(fmt.Sprintf("▶️ [Watch](%s)\n\n", result.Embed))
+	}
+	return sb.String()
+}
+
+func FormatNewsResultsForLLM(query string, results []NewsResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("# GoDuckDuckGo News Results\n\nNo news results found for query: \"%s\"", query)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# GoDuckDuckGo News Results\n\nFound %d news articles for: \"%s\"\n\n---\n\n", len(results), query))
+	for _, result := range results {
+		sb.WriteString(fmt.Sprintf("### %s\n", result.Title))
+		sb.WriteString(fmt.Sprintf("%s — %s\n", result.Source, result.Date))
+		sb.WriteString(fmt.Sprintf("%s\n\n", result.Excerpt))
+		sb.WriteString(fmt.Sprintf("🔗 [Read More](%s)\n\n", result.URL))
+	}
+	return sb.String()
+}
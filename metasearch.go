@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// rrfK is the rank-dampening constant from Reciprocal Rank Fusion: a result
+// ranked lower by one backend still contributes meaningfully to its total
+// score rather than being swamped by whichever backend ranked it #1.
+const rrfK = 60
+
+// MetasearchAggregator fans a query out across several Searcher backends
+// concurrently and merges their results into a single ranked list.
+type MetasearchAggregator struct {
+	backends []Searcher
+}
+
+func NewMetasearchAggregator(backends ...Searcher) *MetasearchAggregator {
+	return &MetasearchAggregator{backends: backends}
+}
+
+type metasearchHit struct {
+	result SearchResult
+	score  float64
+}
+
+// Search queries every backend concurrently, merges results that resolve to
+// the same canonical URL, scores each by reciprocal-rank fusion across the
+// backends that returned it, and returns the topN highest-scoring results.
+func (m *MetasearchAggregator) Search(ctx context.Context, query string, opts SearchOptions, topN int) ([]SearchResult, error) {
+	type backendResult struct {
+		name    string
+		results []SearchResult
+		err     error
+	}
+
+	resultsCh := make(chan backendResult, len(m.backends))
+	var wg sync.WaitGroup
+	for _, backend := range m.backends {
+		wg.Add(1)
+		go func(b Searcher) {
+			defer wg.Done()
+			res, err := b.Search(ctx, query, opts)
+			resultsCh <- backendResult{name: b.Name(), results: res, err: err}
+		}(backend)
+	}
+	wg.Wait()
+	close(resultsCh)
+
+	hits := make(map[string]*metasearchHit)
+	var order []string
+	var failures []string
+	for br := range resultsCh {
+		if br.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", br.name, br.err))
+			continue
+		}
+		for rank, r := range br.results {
+			key := canonicalizeURL(r.Link)
+			if key == "" {
+				continue
+			}
+			score := 1.0 / float64(rrfK+rank+1)
+			if hit, ok := hits[key]; ok {
+				hit.score += score
+			} else {
+				hits[key] = &metasearchHit{result: r, score: score}
+				order = append(order, key)
+			}
+		}
+	}
+	if len(hits) == 0 {
+		if len(failures) > 0 {
+			return nil, fmt.Errorf("all backends failed: %s", strings.Join(failures, "; "))
+		}
+		return nil, nil
+	}
+
+	merged := make([]SearchResult, 0, len(order))
+	for _, key := range order {
+		merged = append(merged, hits[key].result)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return hits[canonicalizeURL(merged[i].Link)].score > hits[canonicalizeURL(merged[j].Link)].score
+	})
+	if topN > 0 && len(merged) > topN {
+		merged = merged[:topN]
+	}
+	for i := range merged {
+		merged[i].Position = i + 1
+	}
+	return merged, nil
+}
+
+// canonicalizeURL normalizes a result link so the same page returned by two
+// different backends (different scheme, "www.", trailing slash, or tracking
+// params) is recognized as the same result.
+func canonicalizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(raw, "/"))
+	}
+	host := strings.ToLower(strings.TrimPrefix(u.Host, "www."))
+	path := strings.TrimSuffix(u.Path, "/")
+
+	q := u.Query()
+	for key := range q {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || lower == "ref" || lower == "fbclid" || lower == "gclid" {
+			q.Del(key)
+		}
+	}
+
+	canon := host + path
+	if encoded := q.Encode(); encoded != "" {
+		canon += "?" + encoded
+	}
+	return canon
+}
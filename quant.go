@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
+)
+
+// QuantSearcher scrapes Qwant's no-JS "lite" HTML search UI
+// (lite.qwant.com), which mirrors the same result set as the main site
+// without requiring its JSON API.
+type QuantSearcher struct {
+	limiter *rate.Limiter
+	client  *http.Client
+}
+
+func NewQuantSearcher() *QuantSearcher {
+	return &QuantSearcher{
+		limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+		client:  newAntiCensorshipClient(),
+	}
+}
+
+func (s *QuantSearcher) Name() string {
+	return "quant"
+}
+
+func (s *QuantSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("p", strconv.Itoa(page))
+	if region := normalizeRegion(opts.Region); region != "" {
+		q.Set("locale", strings.ReplaceAll(region, "-", "_"))
+	}
+	reqURL := "https://lite.qwant.com/?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", uaPool.Pick())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	doc.Find(".result").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		linkElem := sel.Find("a.result__title, a.result__url").First()
+		link, exists := linkElem.Attr("href")
+		if !exists {
+			return true
+		}
+		title := strings.TrimSpace(sel.Find(".result__title").First().Text())
+		if title == "" {
+			title = strings.TrimSpace(linkElem.Text())
+		}
+		snippet := strings.TrimSpace(sel.Find(".result__body, .result__snippet").First().Text())
+		results = append(results, SearchResult{Title: title, Link: link, Snippet: snippet, Position: len(results) + 1})
+		return len(results) < maxResults
+	})
+	return results, nil
+}
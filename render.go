@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// renderTimeout bounds how long a single chromedp render may take, on top
+// of whatever timeout the caller's context already carries.
+const renderTimeout = 20 * time.Second
+
+// networkIdleWindow is how long a page must go without an in-flight
+// request before it's considered settled enough to capture.
+const networkIdleWindow = 500 * time.Millisecond
+
+// networkIdleMaxWait caps how long waitNetworkIdle will hold up a render
+// waiting for quiet. Pages with a persistent connection (analytics
+// beacons, SSE, long-poll XHRs) may never go fully idle; past this point
+// we capture whatever the page has rendered so far instead of failing
+// the whole request on a render that actually succeeded.
+const networkIdleMaxWait = 5 * time.Second
+
+// maxConcurrentRenders caps how many chromedp tabs may be rendering at
+// once. Each render is a real tab in a real Chrome process, so leaving
+// this unbounded under bursty render=always/auto traffic would blow up
+// memory; a shared allocator plus this semaphore keeps it in check.
+const maxConcurrentRenders = 4
+
+var (
+	allocOnce sync.Once
+	allocCtx  context.Context
+
+	renderSem = make(chan struct{}, maxConcurrentRenders)
+)
+
+// sharedAllocator returns the lazily-created, process-wide chromedp
+// allocator. It's created once and reused for every render so each call
+// spawns a new tab rather than a whole new Chrome process.
+func sharedAllocator() context.Context {
+	allocOnce.Do(func() {
+		opts := append(chromedp.DefaultExecAllocatorOptions[:],
+			chromedp.Flag("headless", "new"),
+			chromedp.Flag("no-sandbox", true),
+			chromedp.Flag("disable-gpu", true),
+		)
+		ctx, _ := chromedp.NewExecAllocator(context.Background(), opts...)
+		allocCtx = ctx
+	})
+	return allocCtx
+}
+
+// renderWithChromedp loads urlStr in a tab on the shared headless Chrome
+// allocator and returns the fully rendered DOM as HTML, letting
+// client-side JavaScript run before the page is captured. It's the
+// fallback for pages whose plain HTTP response is just a bare SPA shell.
+func renderWithChromedp(ctx context.Context, urlStr string) (string, error) {
+	select {
+	case renderSem <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-renderSem }()
+
+	taskCtx, cancelTask := chromedp.NewContext(sharedAllocator())
+	defer cancelTask()
+
+	taskCtx, cancelTimeout := context.WithTimeout(taskCtx, renderTimeout)
+	defer cancelTimeout()
+
+	// The task context is rooted off the shared allocator's background
+	// context, not ctx, since the allocator outlives any single call. Tie
+	// this render's lifetime back to the caller's cancellation too.
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancelTask()
+		case <-taskCtx.Done():
+		}
+	}()
+
+	var html string
+	err := chromedp.Run(taskCtx,
+		emulation.SetUserAgentOverride(uaPool.Pick()),
+		chromedp.Navigate(urlStr),
+		waitDocumentComplete(),
+		waitNetworkIdle(networkIdleWindow),
+		chromedp.OuterHTML("html", &html),
+	)
+	if err != nil {
+		return "", fmt.Errorf("chromedp: %w", err)
+	}
+	return html, nil
+}
+
+// waitDocumentComplete polls document.readyState until the page reports
+// "complete", rather than just waiting for a <body> node to exist (which
+// is true almost immediately for an unrendered SPA shell).
+func waitDocumentComplete() chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		for {
+			var state string
+			if err := chromedp.Evaluate(`document.readyState`, &state).Do(ctx); err != nil {
+				return err
+			}
+			if state == "complete" {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}
+}
+
+// waitNetworkIdle waits until the page has gone idle time without any
+// in-flight network request, tracked via the CDP Network domain, so the
+// capture happens after the page's own async fetches have settled rather
+// than at first paint.
+func waitNetworkIdle(idle time.Duration) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var mu sync.Mutex
+		inFlight := 0
+		lastActivity := time.Now()
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch ev.(type) {
+			case *network.EventRequestWillBeSent:
+				mu.Lock()
+				inFlight++
+				lastActivity = time.Now()
+				mu.Unlock()
+			case *network.EventLoadingFinished, *network.EventLoadingFailed:
+				mu.Lock()
+				if inFlight > 0 {
+					inFlight--
+				}
+				lastActivity = time.Now()
+				mu.Unlock()
+			}
+		})
+
+		if err := network.Enable().Do(ctx); err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(networkIdleMaxWait)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				mu.Lock()
+				settled := inFlight == 0 && time.Since(lastActivity) >= idle
+				mu.Unlock()
+				if settled || time.Now().After(deadline) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// looksUnrendered is a heuristic for detecting a document that's still the
+// pre-JavaScript SPA shell: almost no visible text, but a non-trivial
+// number of script tags that presumably build the real page client-side.
+func looksUnrendered(doc *goquery.Document) bool {
+	if doc == nil {
+		return true
+	}
+	text := strings.TrimSpace(doc.Find("body").Text())
+	return len(text) < 200 && doc.Find("script").Length() > 2
+}
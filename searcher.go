@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SearchOptions carries the parameters common to every search backend. Not
+// every backend honors every field (e.g. a backend with no region support
+// simply ignores Region).
+type SearchOptions struct {
+	MaxResults int
+	SafeSearch string
+	Page       int
+	Region     string
+}
+
+// Searcher is implemented by anything that can turn a query into a list of
+// web results. DuckDuckGoSearcher, GoogleSearcher, QuantSearcher, and
+// SearXNGSearcher all implement it so the metasearch aggregator can fan a
+// single query out across backends interchangeably.
+type Searcher interface {
+	Name() string
+	Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error)
+}
+
+// normalizeRegion strips DuckDuckGo's "no region" sentinel ("wt-wt") for
+// backends that don't understand it. The search/metasearch tools default
+// Region to "wt-wt" because that's DuckDuckGoSearcher's convention, but
+// Google, Qwant, and SearXNG have no such value, so it must be treated as
+// "no region requested" rather than sent through literally.
+func normalizeRegion(region string) string {
+	if region == "wt-wt" {
+		return ""
+	}
+	return region
+}
+
+// FormatResultsForLLM renders results as Markdown under the given heading,
+// in the same shape regardless of which backend (or combination of
+// backends) produced them.
+func FormatResultsForLLM(heading, query string, results []SearchResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("# %s\n\nNo results found for query: \"%s\"", heading, query)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\nFound %d results for: \"%s\"\n\n---\n\n", heading, len(results), query))
+	for _, result := range results {
+		sb.WriteString(fmt.Sprintf("### %s\n", result.Title))
+		sb.WriteString(fmt.Sprintf("%s\n\n", result.Snippet))
+		sb.WriteString(fmt.Sprintf("🔗 [Read More](%s)\n\n", result.Link))
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// publicSearXNGInstances seeds the pool with a handful of well-known public
+// instances. Any of them can disappear or go down at any time, which is
+// exactly why the instance selector health-checks before using one.
+var publicSearXNGInstances = []string{
+	"https://searx.be",
+	"https://search.sapti.me",
+	"https://searx.tiekoetter.com",
+	"https://priv.au",
+}
+
+const searxHealthCacheTTL = 10 * time.Minute
+
+// searxInstanceSelector probes the candidate pool and caches which
+// instances are currently healthy, so a dead instance doesn't take down the
+// aggregator and every query doesn't pay the cost of a fresh health check.
+type searxInstanceSelector struct {
+	client *http.Client
+
+	mu        sync.Mutex
+	healthy   []string
+	checkedAt time.Time
+}
+
+func newSearXInstanceSelector(client *http.Client) *searxInstanceSelector {
+	return &searxInstanceSelector{client: client}
+}
+
+func (sel *searxInstanceSelector) pick(ctx context.Context) (string, error) {
+	sel.mu.Lock()
+	if time.Since(sel.checkedAt) < searxHealthCacheTTL && len(sel.healthy) > 0 {
+		instances := sel.healthy
+		sel.mu.Unlock()
+		return instances[rand.Intn(len(instances))], nil
+	}
+	sel.mu.Unlock()
+
+	var healthy []string
+	for _, instance := range publicSearXNGInstances {
+		if sel.probe(ctx, instance) {
+			healthy = append(healthy, instance)
+		}
+	}
+
+	sel.mu.Lock()
+	sel.healthy = healthy
+	sel.checkedAt = time.Now()
+	sel.mu.Unlock()
+
+	if len(healthy) == 0 {
+		return "", fmt.Errorf("no healthy searxng instances")
+	}
+	return healthy[rand.Intn(len(healthy))], nil
+}
+
+func (sel *searxInstanceSelector) probe(ctx context.Context, instance string) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(probeCtx, "GET", instance+"/search?q=test&format=json", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", uaPool.Pick())
+	resp, err := sel.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// SearXNGSearcher queries a rotating pool of public SearXNG instances so no
+// single instance becomes a single point of failure.
+type SearXNGSearcher struct {
+	limiter  *rate.Limiter
+	client   *http.Client
+	selector *searxInstanceSelector
+}
+
+func NewSearXNGSearcher() *SearXNGSearcher {
+	client := newAntiCensorshipClient()
+	return &SearXNGSearcher{
+		limiter:  rate.NewLimiter(rate.Every(time.Second), 1),
+		client:   client,
+		selector: newSearXInstanceSelector(client),
+	}
+}
+
+func (s *SearXNGSearcher) Name() string {
+	return "searxng"
+}
+
+type searxResponse struct {
+	Results []struct {
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Content string `json:"content"`
+	} `json:"results"`
+}
+
+func (s *SearXNGSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+	instance, err := s.selector.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("format", "json")
+	q.Set("pageno", strconv.Itoa(page))
+	if region := normalizeRegion(opts.Region); region != "" {
+		q.Set("language", strings.ToLower(region))
+	}
+	if strings.ToLower(opts.SafeSearch) == "strict" {
+		q.Set("safesearch", "2")
+	} else if strings.ToLower(opts.SafeSearch) == "off" {
+		q.Set("safesearch", "0")
+	} else {
+		q.Set("safesearch", "1")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", instance+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", uaPool.Pick())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	var parsed searxResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, r := range parsed.Results {
+		if len(results) >= maxResults {
+			break
+		}
+		results = append(results, SearchResult{
+			Title:    strings.TrimSpace(r.Title),
+			Link:     r.URL,
+			Snippet:  strings.TrimSpace(r.Content),
+			Position: len(results) + 1,
+		})
+	}
+	return results, nil
+}